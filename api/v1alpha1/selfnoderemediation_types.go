@@ -0,0 +1,103 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RemediationPhase represents the stage a remediation flow has reached.
+type RemediationPhase string
+
+const (
+	// PhasePending means the remediation was just requested and has not
+	// started acting on the node yet.
+	PhasePending RemediationPhase = "Pending"
+	// PhaseNodeIsolated means the node was marked unschedulable and cordoned
+	// off from new workloads.
+	PhaseNodeIsolated RemediationPhase = "NodeIsolated"
+	// PhaseWatchdogTriggered means the agent stopped feeding the watchdog so
+	// the unhealthy node will self-reboot.
+	PhaseWatchdogTriggered RemediationPhase = "WatchdogTriggered"
+	// PhaseNodeDeleted means the Node object was deleted to force the
+	// workloads scheduled on it to be rescheduled elsewhere.
+	PhaseNodeDeleted RemediationPhase = "NodeDeleted"
+	// PhaseNodeRestored means the backed up Node object was recreated once
+	// the node was confirmed to have rebooted.
+	PhaseNodeRestored RemediationPhase = "NodeRestored"
+	// PhaseSucceeded means the remediation flow completed successfully.
+	PhaseSucceeded RemediationPhase = "Succeeded"
+	// PhaseFailed means the remediation flow could not complete.
+	PhaseFailed RemediationPhase = "Failed"
+)
+
+// ConditionType enumerates the condition types reported on a
+// SelfNodeRemediation's status.
+const (
+	// ConditionTypeProcessing is true while the remediation flow is actively
+	// progressing through its phases.
+	ConditionTypeProcessing = "Processing"
+	// ConditionTypeSucceeded is true once the remediation flow has completed
+	// successfully.
+	ConditionTypeSucceeded = "Succeeded"
+)
+
+// PhaseTransition records the time a remediation flow entered a given phase.
+type PhaseTransition struct {
+	Phase RemediationPhase `json:"phase"`
+	Time  metav1.Time      `json:"time"`
+}
+
+// SelfNodeRemediationStatus is the typed status reported for a Machine (or
+// Node) currently going through remediation.
+type SelfNodeRemediationStatus struct {
+	// Phase is the current stage of the remediation flow.
+	Phase RemediationPhase `json:"phase,omitempty"`
+
+	// NodeName is the node this remediation is acting on.
+	NodeName string `json:"nodeName,omitempty"`
+
+	// RetryCount is incremented every time the flow is resumed after having
+	// been interrupted (e.g. by a controller restart).
+	RetryCount int `json:"retryCount,omitempty"`
+
+	// Transitions records the time each phase was entered, oldest first.
+	Transitions []PhaseTransition `json:"transitions,omitempty"`
+
+	// Conditions represent the latest available observations of the
+	// remediation's state.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// SelfNodeRemediation is the Schema for reporting remediation progress for a
+// single unhealthy Machine/Node. It is populated and owned by the machine
+// controller; users are not expected to create it directly.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+type SelfNodeRemediation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status SelfNodeRemediationStatus `json:"status,omitempty"`
+}
+
+// SelfNodeRemediationList contains a list of SelfNodeRemediation
+// +kubebuilder:object:root=true
+type SelfNodeRemediationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SelfNodeRemediation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SelfNodeRemediation{}, &SelfNodeRemediationList{})
+}
+
+// SetPhase appends a new transition if the phase actually changed and
+// updates Status.Phase accordingly.
+func (s *SelfNodeRemediationStatus) SetPhase(phase RemediationPhase, now metav1.Time) {
+	if s.Phase == phase {
+		return
+	}
+	s.Phase = phase
+	s.Transitions = append(s.Transitions, PhaseTransition{Phase: phase, Time: now})
+}
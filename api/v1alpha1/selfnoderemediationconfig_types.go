@@ -0,0 +1,66 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// SelfNodeRemediationConfigSpec configures cluster-wide behavior of the
+// machine controller.
+type SelfNodeRemediationConfigSpec struct {
+	// MaxUnhealthy caps how many Machines may be remediated at the same
+	// time, as an absolute number or a percentage of the total Machine
+	// count (e.g. "40%"). Once the number of Machines currently being
+	// remediated plus the one about to start would exceed this value, the
+	// controller backs off instead of proceeding. A nil value means no
+	// limit.
+	// +optional
+	MaxUnhealthy *intstr.IntOrString `json:"maxUnhealthy,omitempty"`
+
+	// PreferReplacementOverRestore controls what happens to a Machine that
+	// is both unhealthy and flagged as outdated: when true (the default
+	// when unset), the controller deletes the Machine to force a
+	// replacement instead of restoring its backed up Node.
+	// +optional
+	PreferReplacementOverRestore *bool `json:"preferReplacementOverRestore,omitempty"`
+}
+
+// SelfNodeRemediationConfig is the Schema for cluster-wide remediation
+// settings. It is a singleton, cluster-scoped resource.
+// +kubebuilder:object:root=true
+type SelfNodeRemediationConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SelfNodeRemediationConfigSpec `json:"spec,omitempty"`
+}
+
+// SelfNodeRemediationConfigList contains a list of SelfNodeRemediationConfig
+// +kubebuilder:object:root=true
+type SelfNodeRemediationConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SelfNodeRemediationConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SelfNodeRemediationConfig{}, &SelfNodeRemediationConfigList{})
+}
+
+// ValidateMaxUnhealthy rejects malformed MaxUnhealthy values (e.g. a
+// negative int or a percent string that doesn't match "<number>%") the same
+// way the MachineHealthCheck webhook validates its MaxUnhealthy field.
+func ValidateMaxUnhealthy(maxUnhealthy *intstr.IntOrString) error {
+	if maxUnhealthy == nil {
+		return nil
+	}
+	if maxUnhealthy.Type == intstr.Int && maxUnhealthy.IntValue() < 0 {
+		return fmt.Errorf("maxUnhealthy must not be negative, got %d", maxUnhealthy.IntValue())
+	}
+	if _, err := intstr.GetValueFromIntOrPercent(maxUnhealthy, 0, true); err != nil {
+		return fmt.Errorf("invalid maxUnhealthy value %q: %v", maxUnhealthy.String(), err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestValidateMaxUnhealthy(t *testing.T) {
+	cases := map[string]struct {
+		value   *intstr.IntOrString
+		wantErr bool
+	}{
+		"nil is unlimited":     {value: nil, wantErr: false},
+		"zero int":             {value: intOrStringPtr(intstr.FromInt(0)), wantErr: false},
+		"positive int":         {value: intOrStringPtr(intstr.FromInt(3)), wantErr: false},
+		"negative int":         {value: intOrStringPtr(intstr.FromInt(-1)), wantErr: true},
+		"valid percent":        {value: intOrStringPtr(intstr.FromString("40%")), wantErr: false},
+		"zero percent":         {value: intOrStringPtr(intstr.FromString("0%")), wantErr: false},
+		"percent without sign": {value: intOrStringPtr(intstr.FromString("40")), wantErr: true},
+		"garbage string":       {value: intOrStringPtr(intstr.FromString("abc%")), wantErr: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateMaxUnhealthy(tc.value)
+			if tc.wantErr && err == nil {
+				t.Fatalf("expected an error, got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func intOrStringPtr(v intstr.IntOrString) *intstr.IntOrString {
+	return &v
+}
@@ -0,0 +1,153 @@
+// Command agent runs the self-node-remediation machine controller alongside
+// the peerhealth gRPC server that other agents dial into for consensus
+// checks before a remediation starts.
+package main
+
+import (
+	"flag"
+	"os"
+	"sync"
+	"time"
+
+	machinev1beta1 "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/medik8s/self-node-remediation/api/v1alpha1"
+	"github.com/medik8s/self-node-remediation/controllers"
+	"github.com/medik8s/self-node-remediation/pkg/peerhealth"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = machinev1beta1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+}
+
+func main() {
+	var peerHealthAddr string
+	var watchdogDevice string
+	flag.StringVar(&peerHealthAddr, "peer-health-addr", ":30001", "address the peerhealth gRPC server listens on")
+	flag.StringVar(&watchdogDevice, "watchdog-device", "/dev/watchdog", "path to the hardware watchdog device this agent feeds")
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New())
+	setupLog := ctrl.Log.WithName("setup")
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to start manager")
+		os.Exit(1)
+	}
+
+	dog, err := newSoftwareWatchdog(watchdogDevice)
+	if err != nil {
+		setupLog.Error(err, "unable to open watchdog device")
+		os.Exit(1)
+	}
+	if err := dog.Start(); err != nil {
+		setupLog.Error(err, "unable to start watchdog")
+		os.Exit(1)
+	}
+
+	peerClient := peerhealth.NewClient(nil)
+	reconciler := &controllers.MachineReconciler{
+		Client:     mgr.GetClient(),
+		Log:        ctrl.Log.WithName("machine-controller"),
+		Scheme:     mgr.GetScheme(),
+		Watchdog:   dog,
+		Recorder:   mgr.GetEventRecorderFor("self-node-remediation"),
+		PeerHealth: controllers.NewGRPCPeerHealthChecker(mgr.GetClient(), peerClient),
+	}
+	if err := reconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to set up machine controller")
+		os.Exit(1)
+	}
+
+	peerSrv := peerhealth.NewServer(controllers.NewAPIHealthSource(mgr.GetClient()), nil)
+	go func() {
+		if err := peerSrv.Serve(peerHealthAddr); err != nil {
+			setupLog.Error(err, "peerhealth gRPC server stopped")
+			os.Exit(1)
+		}
+	}()
+
+	setupLog.Info("starting manager")
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		setupLog.Error(err, "problem running manager")
+		os.Exit(1)
+	}
+}
+
+// softwareWatchdog feeds the Linux kernel watchdog device at devicePath on a
+// fixed interval, and stops feeding it once Starve is called so the device
+// fires and reboots the node.
+type softwareWatchdog struct {
+	device *os.File
+
+	stop chan struct{}
+
+	lastFoodMu sync.Mutex
+	lastFood   time.Time
+	starved    bool
+}
+
+func newSoftwareWatchdog(devicePath string) (*softwareWatchdog, error) {
+	device, err := os.OpenFile(devicePath, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &softwareWatchdog{device: device, stop: make(chan struct{}), lastFood: time.Now()}, nil
+}
+
+func (d *softwareWatchdog) Start() error {
+	go func() {
+		ticker := time.NewTicker(watchdogFeedInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+				d.lastFoodMu.Lock()
+				starved := d.starved
+				d.lastFoodMu.Unlock()
+				if starved {
+					continue
+				}
+				if _, err := d.device.Write([]byte("\n")); err != nil {
+					ctrl.Log.WithName("watchdog").Error(err, "failed to feed watchdog")
+					continue
+				}
+				d.lastFoodMu.Lock()
+				d.lastFood = time.Now()
+				d.lastFoodMu.Unlock()
+			}
+		}
+	}()
+	return nil
+}
+
+func (d *softwareWatchdog) Feed() {
+	d.lastFoodMu.Lock()
+	defer d.lastFoodMu.Unlock()
+	d.starved = false
+}
+
+func (d *softwareWatchdog) Starve() {
+	d.lastFoodMu.Lock()
+	defer d.lastFoodMu.Unlock()
+	d.starved = true
+}
+
+func (d *softwareWatchdog) GetLastFoodTime() time.Time {
+	d.lastFoodMu.Lock()
+	defer d.lastFoodMu.Unlock()
+	return d.lastFood
+}
+
+const watchdogFeedInterval = 5 * time.Second
@@ -0,0 +1,467 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	machinev1beta1 "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/medik8s/self-node-remediation/api/v1alpha1"
+)
+
+const (
+	// externalRemediationAnnotation, once present on a Machine, signals that
+	// the machine is unhealthy and should be remediated. Once remediation has
+	// started, the machine controller stores a JSON
+	// remediationAnnotationPayload in it.
+	externalRemediationAnnotation = "host.metal3.io/external-remediation"
+
+	// nodeBackupAnnotation holds a JSON serialized copy of the Node object
+	// backing the Machine, taken right before the node is deleted, so it can
+	// be restored once the node has rebooted.
+	nodeBackupAnnotation = "host.metal3.io/node-backup"
+
+	// reconcileInterval is how often the controller re-checks a Machine that
+	// is mid-remediation even without a triggering watch event.
+	reconcileInterval = time.Second
+
+	// safeTimeToAssumeNodeRebooted is how long the controller waits after
+	// starving the watchdog before it assumes the unhealthy node has
+	// rebooted and it is safe to delete and restore the Node object.
+	safeTimeToAssumeNodeRebooted = 90 * time.Second
+)
+
+// NodeUnschedulableTaint mirrors the taint the upstream node lifecycle
+// controller applies to a node once it is marked unschedulable.
+var NodeUnschedulableTaint = &v1.Taint{
+	Key:    "node.kubernetes.io/unschedulable",
+	Effect: v1.TaintEffectNoSchedule,
+}
+
+// remediationAnnotationPayload is the structured value stored under
+// externalRemediationAnnotation.
+type remediationAnnotationPayload struct {
+	UID        types.UID                 `json:"uid"`
+	NodeName   string                    `json:"nodeName"`
+	StartedAt  time.Time                 `json:"startedAt"`
+	RebootedAt time.Time                 `json:"rebootedAt,omitempty"`
+	RetryCount int                       `json:"retryCount"`
+	Phase      v1alpha1.RemediationPhase `json:"phase"`
+}
+
+// MachineReconciler watches Machines flagged for remediation and drives them
+// through the isolate -> reboot -> delete -> restore flow.
+type MachineReconciler struct {
+	client.Client
+	Log        logr.Logger
+	Scheme     *runtime.Scheme
+	Watchdog   Watchdog
+	Recorder   record.EventRecorder
+	PeerHealth PeerHealthChecker
+
+	mu       sync.Mutex
+	statuses map[types.UID]*v1alpha1.SelfNodeRemediationStatus
+}
+
+// Status returns the in-memory SelfNodeRemediation status tracked for the
+// given Machine UID, or nil if that machine is not currently being
+// remediated. Tests use this to assert phase transitions instead of parsing
+// the annotation strings.
+func (r *MachineReconciler) Status(machineUID types.UID) *v1alpha1.SelfNodeRemediationStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.statuses[machineUID]
+}
+
+// setPhase records a phase transition, refreshes its Conditions and persists
+// a snapshot to the Machine's SelfNodeRemediation status CR. Once the phase
+// is terminal (Succeeded/Failed) the in-memory entry is dropped immediately.
+func (r *MachineReconciler) setPhase(ctx context.Context, machine *machinev1beta1.Machine, phase v1alpha1.RemediationPhase) *v1alpha1.SelfNodeRemediationStatus {
+	r.mu.Lock()
+	if r.statuses == nil {
+		r.statuses = map[types.UID]*v1alpha1.SelfNodeRemediationStatus{}
+	}
+	status, ok := r.statuses[machine.UID]
+	if !ok {
+		status = &v1alpha1.SelfNodeRemediationStatus{}
+		if machine.Status.NodeRef != nil {
+			status.NodeName = machine.Status.NodeRef.Name
+		}
+		r.statuses[machine.UID] = status
+	}
+	now := metav1.Now()
+	status.SetPhase(phase, now)
+	updateConditions(status, phase, now)
+	snapshot := *status
+	if phase == v1alpha1.PhaseSucceeded || phase == v1alpha1.PhaseFailed {
+		delete(r.statuses, machine.UID)
+	}
+	r.mu.Unlock()
+
+	r.syncStatusCR(ctx, machine, snapshot)
+	return status
+}
+
+func (r *MachineReconciler) clearStatus(machine *machinev1beta1.Machine) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.statuses, machine.UID)
+}
+
+// updateConditions refreshes the Processing/Succeeded Conditions to reflect
+// the phase just entered.
+func updateConditions(status *v1alpha1.SelfNodeRemediationStatus, phase v1alpha1.RemediationPhase, now metav1.Time) {
+	processing := metav1.ConditionTrue
+	succeeded := metav1.ConditionFalse
+	if phase == v1alpha1.PhaseSucceeded || phase == v1alpha1.PhaseFailed {
+		processing = metav1.ConditionFalse
+	}
+	if phase == v1alpha1.PhaseSucceeded {
+		succeeded = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               v1alpha1.ConditionTypeProcessing,
+		Status:             processing,
+		Reason:             string(phase),
+		LastTransitionTime: now,
+	})
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               v1alpha1.ConditionTypeSucceeded,
+		Status:             succeeded,
+		Reason:             string(phase),
+		LastTransitionTime: now,
+	})
+}
+
+// syncStatusCR best-effort persists status as the SelfNodeRemediation CR
+// named after the Machine, so `kubectl describe` surfaces remediation
+// progress. The CR outlives a controller restart, but a resumed remediation
+// rewrites it from a fresh in-memory status, so transitions recorded before
+// the restart are not kept. Failures are logged rather than propagated:
+// losing this mirror must never block the remediation flow itself.
+func (r *MachineReconciler) syncStatusCR(ctx context.Context, machine *machinev1beta1.Machine, status v1alpha1.SelfNodeRemediationStatus) {
+	key := client.ObjectKey{Name: machine.Name, Namespace: machine.Namespace}
+	cr := &v1alpha1.SelfNodeRemediation{}
+	if err := r.Get(ctx, key, cr); err != nil {
+		if !errors.IsNotFound(err) {
+			r.Log.Error(err, "failed to get SelfNodeRemediation status CR", "machine", machine.Name)
+			return
+		}
+		cr = &v1alpha1.SelfNodeRemediation{ObjectMeta: metav1.ObjectMeta{Name: machine.Name, Namespace: machine.Namespace}}
+		if err := r.Create(ctx, cr); err != nil {
+			r.Log.Error(err, "failed to create SelfNodeRemediation status CR", "machine", machine.Name)
+			return
+		}
+	}
+
+	cr.Status = status
+	if err := r.Status().Update(ctx, cr); err != nil {
+		r.Log.Error(err, "failed to update SelfNodeRemediation status CR", "machine", machine.Name)
+	}
+}
+
+// recordEvent emits a Kubernetes Event on both the Machine and, when known,
+// its backing Node so `kubectl describe` on either surfaces remediation
+// progress.
+func (r *MachineReconciler) recordEvent(ctx context.Context, machine *machinev1beta1.Machine, node *v1.Node, reason, message string) {
+	r.Recorder.Event(machine, v1.EventTypeNormal, reason, message)
+	if node != nil {
+		r.Recorder.Event(node, v1.EventTypeNormal, reason, message)
+	}
+}
+
+func (r *MachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	machine := &machinev1beta1.Machine{}
+	if err := r.Get(ctx, req.NamespacedName, machine); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if _, unhealthy := machine.Annotations[externalRemediationAnnotation]; !unhealthy {
+		return ctrl.Result{}, nil
+	}
+
+	if machine.Status.NodeRef == nil {
+		return ctrl.Result{RequeueAfter: reconcileInterval}, nil
+	}
+
+	if r.Status(machine.UID) == nil {
+		if r.peerConsensusSaysHealthy(ctx, machine.Status.NodeRef.Name) {
+			r.recordEvent(ctx, machine, nil, "RemediationSkippedPeersDisagree",
+				"skipping remediation: peers still see this node as healthy")
+			return ctrl.Result{RequeueAfter: reconcileInterval}, nil
+		}
+
+		exceeded, err := r.maxUnhealthyExceeded(ctx)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if exceeded {
+			r.recordEvent(ctx, machine, nil, "RemediationSkippedTooManyUnhealthy",
+				"skipping remediation: too many Machines are already being remediated cluster-wide")
+			return ctrl.Result{RequeueAfter: tooManyUnhealthyBackoff}, nil
+		}
+	}
+
+	var remediator Remediator
+	switch strategyFor(machine) {
+	case StrategyReprovision:
+		remediator = &reprovisionRemediator{r: r}
+	case StrategyOutOfService:
+		remediator = &outOfServiceRemediator{r: r}
+	default:
+		remediator = &watchdogRebootRemediator{r: r}
+	}
+
+	phase, err := remediator.Remediate(ctx, machine)
+	return resultFor(phase, err)
+}
+
+// resultFor translates a Remediator's outcome into a ctrl.Result, requeuing
+// while the flow is still in progress.
+func resultFor(phase v1alpha1.RemediationPhase, err error) (ctrl.Result, error) {
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if phase == v1alpha1.PhaseSucceeded || phase == v1alpha1.PhaseFailed {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: reconcileInterval}, nil
+}
+
+func (r *MachineReconciler) readAnnotation(machine *machinev1beta1.Machine) (*remediationAnnotationPayload, error) {
+	raw := machine.Annotations[externalRemediationAnnotation]
+	if raw == "" {
+		return nil, nil
+	}
+	payload := &remediationAnnotationPayload{}
+	if err := json.Unmarshal([]byte(raw), payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (r *MachineReconciler) writeAnnotation(ctx context.Context, machine *machinev1beta1.Machine, payload *remediationAnnotationPayload) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	machine.Annotations[externalRemediationAnnotation] = string(raw)
+	return r.Update(ctx, machine)
+}
+
+func (r *MachineReconciler) startRemediation(ctx context.Context, machine *machinev1beta1.Machine, node *v1.Node) (v1alpha1.RemediationPhase, error) {
+	if node != nil && !node.Spec.Unschedulable {
+		node.Spec.Unschedulable = true
+		if err := r.Update(ctx, node); err != nil {
+			return v1alpha1.PhaseFailed, err
+		}
+	}
+	r.setPhase(ctx, machine, v1alpha1.PhaseNodeIsolated)
+	if node != nil && !hasTaint(node, NodeUnschedulableTaint) {
+		// Wait for the node lifecycle controller to taint the node, so the
+		// backup taken below already carries that taint.
+		return v1alpha1.PhaseNodeIsolated, nil
+	}
+	r.recordEvent(ctx, machine, node, "RemediationStarted", "marked node unschedulable, starting remediation")
+
+	if node != nil {
+		backup, err := json.Marshal(node)
+		if err != nil {
+			return v1alpha1.PhaseFailed, err
+		}
+		machine.Annotations[nodeBackupAnnotation] = string(backup)
+	}
+
+	payload := &remediationAnnotationPayload{
+		UID:       machine.UID,
+		StartedAt: time.Now(),
+		Phase:     v1alpha1.PhaseNodeIsolated,
+	}
+	if node != nil {
+		payload.NodeName = node.Name
+	}
+	if err := r.writeAnnotation(ctx, machine, payload); err != nil {
+		return v1alpha1.PhaseFailed, err
+	}
+
+	r.Watchdog.Starve()
+	r.setPhase(ctx, machine, v1alpha1.PhaseWatchdogTriggered)
+	r.recordEvent(ctx, machine, node, "WatchdogStarved", "stopped feeding watchdog, waiting for node to reboot")
+
+	return v1alpha1.PhaseWatchdogTriggered, nil
+}
+
+func (r *MachineReconciler) resumeRemediation(ctx context.Context, machine *machinev1beta1.Machine, node *v1.Node, payload *remediationAnnotationPayload) (v1alpha1.RemediationPhase, error) {
+	if payload.UID != machine.UID {
+		// Machine was replaced mid-flight (e.g. machine-api recreated it);
+		// the old remediation no longer applies, so drop its annotations.
+		// The new incarnation is only remediated once it is flagged
+		// unhealthy again.
+		delete(machine.Annotations, externalRemediationAnnotation)
+		delete(machine.Annotations, nodeBackupAnnotation)
+		r.clearStatus(machine)
+		if err := r.Update(ctx, machine); err != nil {
+			return v1alpha1.PhaseFailed, err
+		}
+		return v1alpha1.PhasePending, nil
+	}
+
+	if !payload.RebootedAt.IsZero() && node != nil && node.CreationTimestamp.After(payload.RebootedAt) {
+		// The node was already restored by a previous incarnation of this
+		// controller (e.g. it crashed right after recreating the node but
+		// before it could clear the annotation). Finish the cleanup instead
+		// of deleting the freshly restored node all over again.
+		return r.finishRemediation(ctx, machine, payload)
+	}
+
+	if r.Status(machine.UID) == nil {
+		// We have a remediation annotation but no in-memory record of it:
+		// the controller must have restarted mid-flight. Resume instead of
+		// restarting the flow from scratch, and keep count of how many
+		// times this has happened.
+		payload.RetryCount++
+		status := r.setPhase(ctx, machine, payload.Phase)
+		r.mu.Lock()
+		status.RetryCount = payload.RetryCount
+		snapshot := *status
+		r.mu.Unlock()
+		r.syncStatusCR(ctx, machine, snapshot)
+		r.recordEvent(ctx, machine, node, "RemediationResumed", "resuming remediation after controller restart")
+		if err := r.writeAnnotation(ctx, machine, payload); err != nil {
+			return v1alpha1.PhaseFailed, err
+		}
+	}
+
+	if time.Since(payload.StartedAt) < safeTimeToAssumeNodeRebooted {
+		return payload.Phase, nil
+	}
+
+	if node != nil {
+		if err := r.Delete(ctx, node); err != nil && !errors.IsNotFound(err) {
+			return v1alpha1.PhaseFailed, err
+		}
+		r.setPhase(ctx, machine, v1alpha1.PhaseNodeDeleted)
+		r.recordEvent(ctx, machine, nil, "NodeDeleted", "deleted node after watchdog reboot window elapsed")
+
+		payload.Phase = v1alpha1.PhaseNodeDeleted
+		if err := r.writeAnnotation(ctx, machine, payload); err != nil {
+			return v1alpha1.PhaseFailed, err
+		}
+		return v1alpha1.PhaseNodeDeleted, nil
+	}
+
+	return r.finishRemediation(ctx, machine, payload)
+}
+
+// finishRemediation restores the node from its backup (if it was not
+// already restored by a previous, crashed incarnation), feeds the watchdog
+// again and clears the remediation annotation. If the Machine is both
+// unhealthy and outdated, it instead deletes the Machine to force a
+// replacement rather than bringing back a node running stale software.
+func (r *MachineReconciler) finishRemediation(ctx context.Context, machine *machinev1beta1.Machine, payload *remediationAnnotationPayload) (v1alpha1.RemediationPhase, error) {
+	if HasUnhealthyOutdatedCondition(machine) {
+		prefer, err := r.preferReplacementOverRestore(ctx)
+		if err != nil {
+			return v1alpha1.PhaseFailed, err
+		}
+		if prefer {
+			return r.replaceOutdatedMachine(ctx, machine)
+		}
+	}
+
+	// Record the reboot before recreating the node, so a restart between the
+	// two finds a node created after RebootedAt and does not delete it again.
+	// CreationTimestamp has one-second resolution, hence the second subtracted.
+	payload.RebootedAt = time.Now().Truncate(time.Second).Add(-time.Second)
+	if err := r.writeAnnotation(ctx, machine, payload); err != nil {
+		return v1alpha1.PhaseFailed, err
+	}
+
+	restored, err := r.restoreNodeFromBackup(ctx, machine)
+	if err != nil {
+		return v1alpha1.PhaseFailed, err
+	}
+	if restored {
+		r.setPhase(ctx, machine, v1alpha1.PhaseNodeRestored)
+		r.recordEvent(ctx, machine, nil, "NodeRestored", "recreated node from backup")
+	}
+
+	delete(machine.Annotations, externalRemediationAnnotation)
+	delete(machine.Annotations, nodeBackupAnnotation)
+	if err := r.Update(ctx, machine); err != nil {
+		return v1alpha1.PhaseFailed, err
+	}
+
+	r.Watchdog.Feed()
+	r.setPhase(ctx, machine, v1alpha1.PhaseSucceeded)
+	r.recordEvent(ctx, machine, nil, "RemediationSucceeded", "remediation completed, watchdog resumed")
+
+	return v1alpha1.PhaseSucceeded, nil
+}
+
+// replaceOutdatedMachine deletes machine without ever restoring its backed
+// up node, so the owning MachineSet provisions a replacement running the
+// current template.
+func (r *MachineReconciler) replaceOutdatedMachine(ctx context.Context, machine *machinev1beta1.Machine) (v1alpha1.RemediationPhase, error) {
+	delete(machine.Annotations, nodeBackupAnnotation)
+
+	if err := r.Delete(ctx, machine); err != nil && !errors.IsNotFound(err) {
+		return v1alpha1.PhaseFailed, err
+	}
+	r.recordEvent(ctx, machine, nil, "MachineReplaced", "machine is outdated, deleting it instead of restoring its node")
+
+	r.setPhase(ctx, machine, v1alpha1.PhaseSucceeded)
+	return v1alpha1.PhaseSucceeded, nil
+}
+
+func (r *MachineReconciler) restoreNodeFromBackup(ctx context.Context, machine *machinev1beta1.Machine) (bool, error) {
+	raw, ok := machine.Annotations[nodeBackupAnnotation]
+	if !ok || raw == "" {
+		return false, nil
+	}
+	node := &v1.Node{}
+	if err := json.Unmarshal([]byte(raw), node); err != nil {
+		return false, err
+	}
+	node.ResourceVersion = ""
+	node.Spec.Unschedulable = false
+	if err := r.Create(ctx, node); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// reconcileRequestFor builds the ctrl.Request a watch event would have
+// produced for the given Machine.
+func reconcileRequestFor(machine *machinev1beta1.Machine) ctrl.Request {
+	return ctrl.Request{NamespacedName: client.ObjectKey{Name: machine.Name, Namespace: machine.Namespace}}
+}
+
+// clientKeyFor builds the client.ObjectKey for a cluster-scoped Node.
+func clientKeyFor(nodeName string) client.ObjectKey {
+	return client.ObjectKey{Name: nodeName}
+}
+
+func (r *MachineReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&machinev1beta1.Machine{}).
+		Complete(r)
+}
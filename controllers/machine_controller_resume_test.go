@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	machinev1beta1 "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/medik8s/self-node-remediation/api/v1alpha1"
+)
+
+// newFreshReconciler returns a reconciler sharing the suite's client but with
+// no in-memory status history, simulating a freshly started controller
+// process picking up where a previous one left off.
+func newFreshReconciler() *MachineReconciler {
+	return &MachineReconciler{
+		Client:   k8sClient,
+		Log:      reconciler.Log,
+		Scheme:   reconciler.Scheme,
+		Watchdog: dummyDog,
+		Recorder: reconciler.Recorder,
+	}
+}
+
+var _ = Describe("Machine Controller re-entrant annotation handling", func() {
+	var machine *machinev1beta1.Machine
+	var node *v1.Node
+
+	BeforeEach(func() {
+		node = &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "resume-node"}}
+		Expect(k8sClient.Create(context.TODO(), node)).To(Succeed())
+
+		machine = &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: "resume-machine", Namespace: machineNamespace},
+		}
+		Expect(k8sClient.Create(context.TODO(), machine)).To(Succeed())
+		machine.Status.NodeRef = &v1.ObjectReference{Kind: "Node", Name: node.Name}
+		Expect(k8sClient.Status().Update(context.TODO(), machine)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = k8sClient.Delete(context.TODO(), machine)
+		_ = k8sClient.Delete(context.TODO(), node)
+	})
+
+	annotate := func(m *machinev1beta1.Machine, payload remediationAnnotationPayload) {
+		raw, err := json.Marshal(payload)
+		Expect(err).ToNot(HaveOccurred())
+		if m.Annotations == nil {
+			m.Annotations = map[string]string{}
+		}
+		m.Annotations[externalRemediationAnnotation] = string(raw)
+		Expect(k8sClient.Update(context.TODO(), m)).To(Succeed())
+	}
+
+	Context("controller crashed after the node was deleted but before it was restored", func() {
+		It("resumes by restoring the node instead of restarting the flow", func() {
+			Expect(k8sClient.Delete(context.TODO(), node)).To(Succeed())
+
+			backup, err := json.Marshal(node)
+			Expect(err).ToNot(HaveOccurred())
+			machine.Annotations = map[string]string{nodeBackupAnnotation: string(backup)}
+			annotate(machine, remediationAnnotationPayload{
+				UID:       machine.UID,
+				NodeName:  node.Name,
+				StartedAt: time.Now().Add(-2 * safeTimeToAssumeNodeRebooted),
+				Phase:     v1alpha1.PhaseNodeDeleted,
+			})
+
+			fresh := newFreshReconciler()
+			_, err = fresh.Reconcile(context.TODO(), reconcileRequestFor(machine))
+			Expect(err).ToNot(HaveOccurred())
+
+			Eventually(func() error {
+				return k8sClient.Get(context.TODO(), client.ObjectKey{Name: node.Name}, &v1.Node{})
+			}, 5*time.Second, 250*time.Millisecond).Should(Succeed())
+
+			// The in-memory status is cleared as soon as the remediation
+			// reaches a terminal phase, so read the retry count back from the
+			// persisted SelfNodeRemediation CR instead.
+			Eventually(func() int {
+				cr := &v1alpha1.SelfNodeRemediation{}
+				Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: machine.Name, Namespace: machine.Namespace}, cr)).To(Succeed())
+				return cr.Status.RetryCount
+			}, 5*time.Second, 250*time.Millisecond).Should(Equal(1))
+		})
+	})
+
+	Context("the Machine was replaced with a new UID mid-flight", func() {
+		It("drops the stale remediation instead of acting on the new machine", func() {
+			staleUID := types.UID("stale-uid")
+			annotate(machine, remediationAnnotationPayload{
+				UID:       staleUID,
+				NodeName:  node.Name,
+				StartedAt: time.Now().Add(-2 * safeTimeToAssumeNodeRebooted),
+				Phase:     v1alpha1.PhaseNodeDeleted,
+			})
+			Expect(machine.UID).ToNot(Equal(staleUID))
+
+			fresh := newFreshReconciler()
+			_, err := fresh.Reconcile(context.TODO(), reconcileRequestFor(machine))
+			Expect(err).ToNot(HaveOccurred())
+
+			Eventually(func() map[string]string {
+				m := &machinev1beta1.Machine{}
+				Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: machine.Name, Namespace: machine.Namespace}, m)).To(Succeed())
+				return m.Annotations
+			}, 5*time.Second, 250*time.Millisecond).ShouldNot(HaveKey(externalRemediationAnnotation))
+
+			Expect(fresh.Status(machine.UID)).To(BeNil())
+
+			node := &v1.Node{}
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: "resume-node"}, node)).To(Succeed())
+		})
+	})
+
+	Context("a stale annotation from a previous incarnation whose node was already restored", func() {
+		It("clears the annotation without deleting the freshly restored node", func() {
+			annotate(machine, remediationAnnotationPayload{
+				UID:        machine.UID,
+				NodeName:   node.Name,
+				StartedAt:  time.Now().Add(-2 * safeTimeToAssumeNodeRebooted),
+				RebootedAt: node.CreationTimestamp.Add(-time.Minute),
+				Phase:      v1alpha1.PhaseNodeRestored,
+			})
+
+			fresh := newFreshReconciler()
+			_, err := fresh.Reconcile(context.TODO(), reconcileRequestFor(machine))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: node.Name}, &v1.Node{})).To(Succeed())
+
+			// The in-memory status is cleared as soon as the remediation
+			// reaches a terminal phase, so assert against the persisted
+			// SelfNodeRemediation CR instead.
+			Eventually(func() v1alpha1.RemediationPhase {
+				cr := &v1alpha1.SelfNodeRemediation{}
+				Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: machine.Name, Namespace: machine.Namespace}, cr)).To(Succeed())
+				return cr.Status.Phase
+			}, 5*time.Second, 250*time.Millisecond).Should(Equal(v1alpha1.PhaseSucceeded))
+		})
+	})
+})
@@ -14,6 +14,8 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"time"
+
+	"github.com/medik8s/self-node-remediation/api/v1alpha1"
 )
 
 var _ = Describe("Machine Controller", func() {
@@ -61,6 +63,16 @@ var _ = Describe("Machine Controller", func() {
 			}, 5*time.Second, 250*time.Millisecond).Should(BeTrue())
 		})
 
+		It("Verify that the remediation status reached NodeIsolated", func() {
+			Eventually(func() v1alpha1.RemediationPhase {
+				status := reconciler.Status(machine1.UID)
+				if status == nil {
+					return ""
+				}
+				return status.Phase
+			}, 5*time.Second, 250*time.Millisecond).Should(Equal(v1alpha1.PhaseNodeIsolated))
+		})
+
 		It("Add unshedulable taint to node to simulate node controller", func() {
 			node.Spec.Taints = append(node.Spec.Taints, *NodeUnschedulableTaint)
 			Expect(k8sClient.Update(context.TODO(), node)).To(Succeed())
@@ -92,6 +104,12 @@ var _ = Describe("Machine Controller", func() {
 			Expect(nodeToRestore).To(Equal(node))
 		})
 
+		It("Verify that the remediation status reached WatchdogTriggered", func() {
+			Eventually(func() v1alpha1.RemediationPhase {
+				return reconciler.Status(machine1.UID).Phase
+			}, 5*time.Second, 250*time.Millisecond).Should(Equal(v1alpha1.PhaseWatchdogTriggered))
+		})
+
 		It("Verify that watchdog is not receiving food", func() {
 			currentLastFoodTime := dummyDog.GetLastFoodTime()
 			Consistently(func() time.Time {
@@ -101,8 +119,14 @@ var _ = Describe("Machine Controller", func() {
 
 		now := time.Now()
 		It("Update annotation time to accelerate the progress", func() {
-			oldTime := now.Add(-safeTimeToAssumeNodeRebooted).Add(-time.Minute)
-			machine1.Annotations[externalRemediationAnnotation] = oldTime.Format(time.RFC3339)
+			payload := &remediationAnnotationPayload{}
+			Expect(json.Unmarshal([]byte(machine1.Annotations[externalRemediationAnnotation]), payload)).To(Succeed())
+
+			payload.StartedAt = now.Add(-safeTimeToAssumeNodeRebooted).Add(-time.Minute)
+			raw, err := json.Marshal(payload)
+			Expect(err).ToNot(HaveOccurred())
+
+			machine1.Annotations[externalRemediationAnnotation] = string(raw)
 			Expect(k8sClient.Update(context.TODO(), machine1)).To(Succeed())
 		})
 
@@ -119,6 +143,12 @@ var _ = Describe("Machine Controller", func() {
 			}, 2*time.Second, 20*time.Millisecond).Should(Equal(metav1.StatusReasonNotFound))
 		})
 
+		It("Verify that the remediation status reached NodeDeleted", func() {
+			Eventually(func() v1alpha1.RemediationPhase {
+				return reconciler.Status(machine1.UID).Phase
+			}, 5*time.Second, 250*time.Millisecond).Should(Equal(v1alpha1.PhaseNodeDeleted))
+		})
+
 		It("Verify that node has been restored", func() {
 			node = &v1.Node{}
 
@@ -141,5 +171,30 @@ var _ = Describe("Machine Controller", func() {
 			}, 5*time.Second, 250*time.Millisecond).ShouldNot(HaveKey(externalRemediationAnnotation))
 
 		})
+
+		It("Verify that the remediation status reached Succeeded with a full phase history", func() {
+			// The in-memory status is cleared once the remediation reaches a
+			// terminal phase, so the final history is read back from the
+			// persisted SelfNodeRemediation CR instead of reconciler.Status.
+			cr := &v1alpha1.SelfNodeRemediation{}
+			Eventually(func() v1alpha1.RemediationPhase {
+				Expect(k8sClient.Get(context.TODO(), machineNamespacedName, cr)).To(Succeed())
+				return cr.Status.Phase
+			}, 5*time.Second, 250*time.Millisecond).Should(Equal(v1alpha1.PhaseSucceeded))
+
+			var phases []v1alpha1.RemediationPhase
+			for _, t := range cr.Status.Transitions {
+				phases = append(phases, t.Phase)
+			}
+			Expect(phases).To(Equal([]v1alpha1.RemediationPhase{
+				v1alpha1.PhaseNodeIsolated,
+				v1alpha1.PhaseWatchdogTriggered,
+				v1alpha1.PhaseNodeDeleted,
+				v1alpha1.PhaseNodeRestored,
+				v1alpha1.PhaseSucceeded,
+			}))
+
+			Expect(reconciler.Status(machine1.UID)).To(BeNil())
+		})
 	})
 })
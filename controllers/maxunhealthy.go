@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	machinev1beta1 "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/medik8s/self-node-remediation/api/v1alpha1"
+)
+
+// configName is the well-known name of the singleton cluster-wide
+// SelfNodeRemediationConfig.
+const configName = "self-node-remediation-config"
+
+// tooManyUnhealthyBackoff is how long the controller waits before
+// re-checking the MaxUnhealthy gate for a Machine it skipped.
+const tooManyUnhealthyBackoff = 30 * time.Second
+
+// maxUnhealthyExceeded reports whether starting another remediation would
+// push the number of Machines concurrently being remediated past the
+// configured MaxUnhealthy. A missing config means no limit is enforced.
+//
+// The in-progress count is derived from cluster-wide Machine state (which
+// Machines still carry externalRemediationAnnotation), not this process's
+// in-memory status map: that map is per-reconciler and empty after a
+// restart, which would let the gate go unenforced during exactly the
+// multi-agent race it exists to guard against.
+func (r *MachineReconciler) maxUnhealthyExceeded(ctx context.Context) (bool, error) {
+	cfg := &v1alpha1.SelfNodeRemediationConfig{}
+	if err := r.Get(ctx, client.ObjectKey{Name: configName}, cfg); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if cfg.Spec.MaxUnhealthy == nil {
+		return false, nil
+	}
+
+	machines := &machinev1beta1.MachineList{}
+	if err := r.List(ctx, machines, client.InNamespace(machineNamespace)); err != nil {
+		return false, err
+	}
+
+	maxUnhealthy, err := intstr.GetValueFromIntOrPercent(cfg.Spec.MaxUnhealthy, len(machines.Items), true)
+	if err != nil {
+		return false, err
+	}
+
+	return remediatingCount(machines) > maxUnhealthy, nil
+}
+
+// remediatingCount returns how many Machines in the list still carry
+// externalRemediationAnnotation, i.e. are still mid-remediation somewhere in
+// the cluster. The watchdog-reboot and out-of-service strategies clear the
+// annotation once they succeed; the reprovision strategy and the outdated
+// machine replacement delete the Machine instead, so it counts here until
+// the Machine is gone. A remediation that fails keeps its annotation and
+// keeps counting. The count reflects every agent's view of the cluster
+// rather than just this reconciler's.
+func remediatingCount(machines *machinev1beta1.MachineList) int {
+	count := 0
+	for i := range machines.Items {
+		if _, unhealthy := machines.Items[i].Annotations[externalRemediationAnnotation]; unhealthy {
+			count++
+		}
+	}
+	return count
+}
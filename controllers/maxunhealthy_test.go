@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	machinev1beta1 "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/medik8s/self-node-remediation/api/v1alpha1"
+)
+
+var _ = Describe("MaxUnhealthy concurrency gate", func() {
+	AfterEach(func() {
+		cfg := &v1alpha1.SelfNodeRemediationConfig{ObjectMeta: metav1.ObjectMeta{Name: configName}}
+		_ = k8sClient.Delete(context.TODO(), cfg)
+	})
+
+	It("skips starting remediation once MaxUnhealthy would be exceeded", func() {
+		maxUnhealthy := intstr.FromInt(0)
+		cfg := &v1alpha1.SelfNodeRemediationConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: configName},
+			Spec:       v1alpha1.SelfNodeRemediationConfigSpec{MaxUnhealthy: &maxUnhealthy},
+		}
+		Expect(k8sClient.Create(context.TODO(), cfg)).To(Succeed())
+
+		node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "gated-node"}}
+		Expect(k8sClient.Create(context.TODO(), node)).To(Succeed())
+
+		machine := &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "gated-machine",
+				Namespace:   machineNamespace,
+				Annotations: map[string]string{externalRemediationAnnotation: ""},
+			},
+		}
+		Expect(k8sClient.Create(context.TODO(), machine)).To(Succeed())
+		machine.Status.NodeRef = &v1.ObjectReference{Kind: "Node", Name: node.Name}
+		Expect(k8sClient.Status().Update(context.TODO(), machine)).To(Succeed())
+
+		fresh := newFreshReconciler()
+		result, err := fresh.Reconcile(context.TODO(), reconcileRequestFor(machine))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(tooManyUnhealthyBackoff))
+
+		Expect(fresh.Status(machine.UID)).To(BeNil())
+
+		Consistently(func() bool {
+			n := &v1.Node{}
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: node.Name}, n)).To(Succeed())
+			return n.Spec.Unschedulable
+		}, time.Second, 100*time.Millisecond).Should(BeFalse())
+
+		Expect(k8sClient.Delete(context.TODO(), machine)).To(Succeed())
+		Expect(k8sClient.Delete(context.TODO(), node)).To(Succeed())
+	})
+
+	It("does not count a remediation that already reached a terminal phase", func() {
+		maxUnhealthy := intstr.FromInt(1)
+		cfg := &v1alpha1.SelfNodeRemediationConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: configName},
+			Spec:       v1alpha1.SelfNodeRemediationConfigSpec{MaxUnhealthy: &maxUnhealthy},
+		}
+		Expect(k8sClient.Create(context.TODO(), cfg)).To(Succeed())
+
+		finishedNode := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "finished-node"}}
+		Expect(k8sClient.Create(context.TODO(), finishedNode)).To(Succeed())
+		finishedMachine := &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "finished-machine",
+				Namespace: machineNamespace,
+				Annotations: map[string]string{
+					externalRemediationAnnotation: "",
+					remediationStrategyAnnotation: string(StrategyOutOfService),
+				},
+			},
+		}
+		Expect(k8sClient.Create(context.TODO(), finishedMachine)).To(Succeed())
+		finishedMachine.Status.NodeRef = &v1.ObjectReference{Kind: "Node", Name: finishedNode.Name}
+		Expect(k8sClient.Status().Update(context.TODO(), finishedMachine)).To(Succeed())
+
+		fresh := newFreshReconciler()
+		_, err := fresh.Reconcile(context.TODO(), reconcileRequestFor(finishedMachine))
+		Expect(err).ToNot(HaveOccurred())
+
+		// The finished remediation is dropped from the in-memory tracker and
+		// has cleared its externalRemediationAnnotation, so it does not count
+		// toward MaxUnhealthy for a second, still-unhealthy Machine.
+		Expect(fresh.Status(finishedMachine.UID)).To(BeNil())
+
+		node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "gated-node-2"}}
+		Expect(k8sClient.Create(context.TODO(), node)).To(Succeed())
+		machine := &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "gated-machine-2",
+				Namespace:   machineNamespace,
+				Annotations: map[string]string{externalRemediationAnnotation: ""},
+			},
+		}
+		Expect(k8sClient.Create(context.TODO(), machine)).To(Succeed())
+		machine.Status.NodeRef = &v1.ObjectReference{Kind: "Node", Name: node.Name}
+		Expect(k8sClient.Status().Update(context.TODO(), machine)).To(Succeed())
+
+		result, err := fresh.Reconcile(context.TODO(), reconcileRequestFor(machine))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).ToNot(Equal(tooManyUnhealthyBackoff))
+
+		Expect(k8sClient.Delete(context.TODO(), finishedMachine)).To(Succeed())
+		Expect(k8sClient.Delete(context.TODO(), finishedNode)).To(Succeed())
+		Expect(k8sClient.Delete(context.TODO(), machine)).To(Succeed())
+		Expect(k8sClient.Delete(context.TODO(), node)).To(Succeed())
+	})
+})
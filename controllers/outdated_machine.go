@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"context"
+
+	machinev1beta1 "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/medik8s/self-node-remediation/api/v1alpha1"
+)
+
+// outdatedAnnotation, when set to "true" on a Machine, flags it as running
+// an outdated template (e.g. its spec.providerID no longer matches the
+// MachineSet's desired template hash). It is maintained by whatever
+// controller computes that hash; this controller only reads it.
+const outdatedAnnotation = "machine.openshift.io/outdated"
+
+// HasUnhealthyOutdatedCondition reports whether machine is both unhealthy
+// (flagged for remediation) and outdated, meaning restoring its backed up
+// Node would just bring back a node running stale software instead of
+// letting the MachineSet roll out a current one.
+func HasUnhealthyOutdatedCondition(machine *machinev1beta1.Machine) bool {
+	_, unhealthy := machine.Annotations[externalRemediationAnnotation]
+	return unhealthy && machine.Annotations[outdatedAnnotation] == "true"
+}
+
+// preferReplacementOverRestore reads SelfNodeRemediationConfig's
+// PreferReplacementOverRestore toggle, defaulting to true (replace outdated
+// machines rather than restoring their node) when no config exists.
+func (r *MachineReconciler) preferReplacementOverRestore(ctx context.Context) (bool, error) {
+	cfg := &v1alpha1.SelfNodeRemediationConfig{}
+	if err := r.Get(ctx, client.ObjectKey{Name: configName}, cfg); err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	if cfg.Spec.PreferReplacementOverRestore == nil {
+		return true, nil
+	}
+	return *cfg.Spec.PreferReplacementOverRestore, nil
+}
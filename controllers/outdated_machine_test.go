@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	machinev1beta1 "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Outdated unhealthy machines", func() {
+	It("deletes the machine instead of restoring its node", func() {
+		node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "outdated-node"}}
+		Expect(k8sClient.Create(context.TODO(), node)).To(Succeed())
+
+		backup, err := json.Marshal(node)
+		Expect(err).ToNot(HaveOccurred())
+
+		machine := &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "outdated-machine",
+				Namespace: machineNamespace,
+				Annotations: map[string]string{
+					outdatedAnnotation:   "true",
+					nodeBackupAnnotation: string(backup),
+				},
+			},
+		}
+		Expect(k8sClient.Create(context.TODO(), machine)).To(Succeed())
+		machine.Status.NodeRef = &v1.ObjectReference{Kind: "Node", Name: node.Name}
+		Expect(k8sClient.Status().Update(context.TODO(), machine)).To(Succeed())
+
+		Expect(k8sClient.Delete(context.TODO(), node)).To(Succeed())
+
+		payload := remediationAnnotationPayload{
+			UID:       machine.UID,
+			NodeName:  node.Name,
+			StartedAt: time.Now().Add(-2 * safeTimeToAssumeNodeRebooted),
+		}
+		raw, err := json.Marshal(payload)
+		Expect(err).ToNot(HaveOccurred())
+		machine.Annotations[externalRemediationAnnotation] = string(raw)
+		Expect(k8sClient.Update(context.TODO(), machine)).To(Succeed())
+
+		Expect(HasUnhealthyOutdatedCondition(machine)).To(BeTrue())
+
+		fresh := newFreshReconciler()
+		_, err = fresh.Reconcile(context.TODO(), reconcileRequestFor(machine))
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() error {
+			return k8sClient.Get(context.TODO(), client.ObjectKey{Name: machine.Name, Namespace: machine.Namespace}, &machinev1beta1.Machine{})
+		}, 5*time.Second, 250*time.Millisecond).Should(Satisfy(errors.IsNotFound))
+
+		// The node must never have been recreated from the backup: the
+		// machine was deleted outright instead.
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: node.Name}, &v1.Node{})).To(HaveOccurred())
+	})
+})
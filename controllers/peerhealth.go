@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	peerhealthv1 "github.com/medik8s/self-node-remediation/api/grpc/peerhealth/v1"
+	"github.com/medik8s/self-node-remediation/pkg/peerhealth"
+)
+
+// PeerHealthChecker corroborates a Machine's unhealthy annotation against
+// what other agents in the cluster can see, over the gRPC peer-health
+// protocol (falling back to HTTP internally when a peer's gRPC port is
+// unreachable). A nil PeerHealthChecker on the reconciler disables this
+// check entirely, matching the controller's pre-gRPC behavior.
+type PeerHealthChecker interface {
+	Confirm(ctx context.Context, nodeName string) (peerhealthv1.HealthResult, error)
+}
+
+// peerConsensusSaysHealthy asks the configured PeerHealthChecker, if any,
+// whether peers still see nodeName as healthy despite the unhealthy
+// annotation. A checker error is treated as "can't tell" (false), so a
+// flaky peer-health round never blocks a genuine remediation.
+func (r *MachineReconciler) peerConsensusSaysHealthy(ctx context.Context, nodeName string) bool {
+	if r.PeerHealth == nil {
+		return false
+	}
+	result, err := r.PeerHealth.Confirm(ctx, nodeName)
+	if err != nil {
+		return false
+	}
+	return result == peerhealthv1.HealthResult_HEALTHY
+}
+
+// grpcPeerHealthChecker implements PeerHealthChecker by asking every other
+// Node's agent, over the peerhealth protocol, whether it still sees nodeName
+// as healthy.
+type grpcPeerHealthChecker struct {
+	client.Client
+	peerClient *peerhealth.Client
+}
+
+// NewGRPCPeerHealthChecker builds a PeerHealthChecker that dials every other
+// Node's agent through peerClient to corroborate an unhealthy verdict before
+// remediation starts.
+func NewGRPCPeerHealthChecker(c client.Client, peerClient *peerhealth.Client) PeerHealthChecker {
+	return &grpcPeerHealthChecker{Client: c, peerClient: peerClient}
+}
+
+// Confirm asks every other Node's agent whether nodeName still looks
+// healthy to it, and reports HEALTHY as soon as one of them agrees. Peers
+// that can't be reached are skipped rather than counted as disagreeing,
+// since a partitioned peer says nothing about the node under test.
+func (g *grpcPeerHealthChecker) Confirm(ctx context.Context, nodeName string) (peerhealthv1.HealthResult, error) {
+	nodes := &v1.NodeList{}
+	if err := g.List(ctx, nodes); err != nil {
+		return peerhealthv1.HealthResult_API_ERROR, err
+	}
+
+	asked := 0
+	for i := range nodes.Items {
+		peer := &nodes.Items[i]
+		if peer.Name == nodeName {
+			continue
+		}
+		address := peerAddress(peer)
+		if address == "" {
+			continue
+		}
+
+		result, err := g.peerClient.IsHealthy(ctx, address, nodeName)
+		if err != nil {
+			continue
+		}
+		asked++
+		if result == peerhealthv1.HealthResult_HEALTHY {
+			return peerhealthv1.HealthResult_HEALTHY, nil
+		}
+	}
+	if asked == 0 {
+		return peerhealthv1.HealthResult_API_ERROR, fmt.Errorf("no reachable peers to confirm health of node %s", nodeName)
+	}
+	return peerhealthv1.HealthResult_UNHEALTHY, nil
+}
+
+// peerAddress returns the address other agents use to reach node's
+// peerhealth endpoint, preferring its internal IP.
+func peerAddress(node *v1.Node) string {
+	for _, addr := range node.Status.Addresses {
+		if addr.Type == v1.NodeInternalIP {
+			return addr.Address
+		}
+	}
+	return ""
+}
+
+// APIHealthSource implements peerhealth.HealthSource by reporting the
+// requested node's standard Ready condition as seen through the shared
+// Kubernetes API. It lets this agent's peerhealth.Server answer peer
+// requests without needing its own independent reachability probe.
+type APIHealthSource struct {
+	client.Client
+}
+
+// NewAPIHealthSource builds an APIHealthSource backed by c.
+func NewAPIHealthSource(c client.Client) *APIHealthSource {
+	return &APIHealthSource{Client: c}
+}
+
+// IsHealthy implements peerhealth.HealthSource.
+func (a *APIHealthSource) IsHealthy(nodeName string) peerhealthv1.HealthResult {
+	node := &v1.Node{}
+	if err := a.Get(context.Background(), client.ObjectKey{Name: nodeName}, node); err != nil {
+		return peerhealthv1.HealthResult_API_ERROR
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type != v1.NodeReady {
+			continue
+		}
+		if cond.Status == v1.ConditionTrue {
+			return peerhealthv1.HealthResult_HEALTHY
+		}
+		return peerhealthv1.HealthResult_UNHEALTHY
+	}
+	return peerhealthv1.HealthResult_API_ERROR
+}
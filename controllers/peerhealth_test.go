@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	machinev1beta1 "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	peerhealthv1 "github.com/medik8s/self-node-remediation/api/grpc/peerhealth/v1"
+)
+
+type fakePeerHealthChecker struct {
+	result peerhealthv1.HealthResult
+}
+
+func (f *fakePeerHealthChecker) Confirm(context.Context, string) (peerhealthv1.HealthResult, error) {
+	return f.result, nil
+}
+
+var _ = Describe("Peer health consensus check", func() {
+	It("skips remediation when peers still see the node as healthy", func() {
+		node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "peer-checked-node"}}
+		Expect(k8sClient.Create(context.TODO(), node)).To(Succeed())
+
+		machine := &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "peer-checked-machine",
+				Namespace:   machineNamespace,
+				Annotations: map[string]string{externalRemediationAnnotation: ""},
+			},
+		}
+		Expect(k8sClient.Create(context.TODO(), machine)).To(Succeed())
+		machine.Status.NodeRef = &v1.ObjectReference{Kind: "Node", Name: node.Name}
+		Expect(k8sClient.Status().Update(context.TODO(), machine)).To(Succeed())
+
+		fresh := newFreshReconciler()
+		fresh.PeerHealth = &fakePeerHealthChecker{result: peerhealthv1.HealthResult_HEALTHY}
+
+		_, err := fresh.Reconcile(context.TODO(), reconcileRequestFor(machine))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fresh.Status(machine.UID)).To(BeNil())
+
+		Consistently(func() bool {
+			n := &v1.Node{}
+			Expect(k8sClient.Get(context.TODO(), clientKeyFor(node.Name), n)).To(Succeed())
+			return n.Spec.Unschedulable
+		}, time.Second, 100*time.Millisecond).Should(BeFalse())
+
+		Expect(k8sClient.Delete(context.TODO(), machine)).To(Succeed())
+		Expect(k8sClient.Delete(context.TODO(), node)).To(Succeed())
+	})
+})
@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"context"
+
+	machinev1beta1 "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/medik8s/self-node-remediation/api/v1alpha1"
+)
+
+// RemediationStrategy selects how an unhealthy Machine is remediated. Only
+// StrategyWatchdogReboot starves the watchdog.
+type RemediationStrategy string
+
+const (
+	// StrategyWatchdogReboot is the default strategy: delete the Node once
+	// the watchdog reboot window has elapsed and restore it from backup
+	// once the node is confirmed back.
+	StrategyWatchdogReboot RemediationStrategy = "WatchdogReboot"
+
+	// StrategyReprovision deletes the Machine itself instead of restoring
+	// the Node, so the machine-api provisions a brand new one.
+	StrategyReprovision RemediationStrategy = "Reprovision"
+
+	// StrategyOutOfService applies the node.kubernetes.io/out-of-service
+	// taint so kube-controller-manager force-detaches volumes and evicts
+	// pods without the Node ever being deleted.
+	StrategyOutOfService RemediationStrategy = "OutOfService"
+)
+
+// remediationStrategyAnnotation lets a Machine opt into a non-default
+// remediation strategy.
+const remediationStrategyAnnotation = "self-node-remediation.medik8s.io/strategy"
+
+// outOfServiceTaint is applied to the node under the OutOfService strategy.
+var outOfServiceTaint = &v1.Taint{
+	Key:    "node.kubernetes.io/out-of-service",
+	Value:  "nodeshutdown",
+	Effect: v1.TaintEffectNoExecute,
+}
+
+// Remediator implements one remediation strategy. It is handed a Machine
+// that has already been confirmed unhealthy and is responsible for driving
+// it to a terminal phase.
+type Remediator interface {
+	Remediate(ctx context.Context, machine *machinev1beta1.Machine) (v1alpha1.RemediationPhase, error)
+}
+
+// strategyFor returns the RemediationStrategy requested on the Machine,
+// defaulting to StrategyWatchdogReboot when unset or unrecognized.
+func strategyFor(machine *machinev1beta1.Machine) RemediationStrategy {
+	switch RemediationStrategy(machine.Annotations[remediationStrategyAnnotation]) {
+	case StrategyReprovision:
+		return StrategyReprovision
+	case StrategyOutOfService:
+		return StrategyOutOfService
+	default:
+		return StrategyWatchdogReboot
+	}
+}
+
+// watchdogRebootRemediator wraps the original start/resume flow (starve the
+// watchdog, delete the node once the reboot window elapses, restore it from
+// backup) behind the Remediator interface.
+type watchdogRebootRemediator struct {
+	r *MachineReconciler
+}
+
+func (wr *watchdogRebootRemediator) Remediate(ctx context.Context, machine *machinev1beta1.Machine) (v1alpha1.RemediationPhase, error) {
+	r := wr.r
+
+	var node *v1.Node
+	if machine.Status.NodeRef != nil {
+		node = &v1.Node{}
+		if err := r.Get(ctx, clientKeyFor(machine.Status.NodeRef.Name), node); err != nil {
+			if !errors.IsNotFound(err) {
+				return v1alpha1.PhaseFailed, err
+			}
+			node = nil
+		}
+	}
+
+	payload, err := r.readAnnotation(machine)
+	if err != nil {
+		r.Log.Error(err, "failed to parse remediation annotation, restarting flow")
+		payload = nil
+	}
+
+	if payload == nil {
+		return r.startRemediation(ctx, machine, node)
+	}
+	return r.resumeRemediation(ctx, machine, node, payload)
+}
+
+// reprovisionRemediator deletes the Machine so machine-api provisions a
+// replacement, rather than restoring the backed up Node.
+type reprovisionRemediator struct {
+	r *MachineReconciler
+}
+
+func (rr *reprovisionRemediator) Remediate(ctx context.Context, machine *machinev1beta1.Machine) (v1alpha1.RemediationPhase, error) {
+	r := rr.r
+
+	var node *v1.Node
+	if machine.Status.NodeRef != nil {
+		node = &v1.Node{}
+		if err := r.Get(ctx, clientKeyFor(machine.Status.NodeRef.Name), node); err != nil {
+			if !errors.IsNotFound(err) {
+				return v1alpha1.PhaseFailed, err
+			}
+			node = nil
+		}
+	}
+
+	if node != nil && !node.Spec.Unschedulable {
+		node.Spec.Unschedulable = true
+		if err := r.Update(ctx, node); err != nil {
+			return v1alpha1.PhaseFailed, err
+		}
+		r.setPhase(ctx, machine, v1alpha1.PhaseNodeIsolated)
+		r.recordEvent(ctx, machine, node, "RemediationStarted", "marked node unschedulable, reprovisioning machine")
+	}
+
+	if machine.DeletionTimestamp == nil {
+		if err := r.Delete(ctx, machine); err != nil && !errors.IsNotFound(err) {
+			return v1alpha1.PhaseFailed, err
+		}
+		r.recordEvent(ctx, machine, node, "MachineDeleted", "deleted machine to force reprovisioning")
+		r.setPhase(ctx, machine, v1alpha1.PhaseNodeDeleted)
+		return v1alpha1.PhaseNodeDeleted, nil
+	}
+
+	r.setPhase(ctx, machine, v1alpha1.PhaseSucceeded)
+	return v1alpha1.PhaseSucceeded, nil
+}
+
+// outOfServiceRemediator taints the node out-of-service instead of deleting
+// it, letting kube-controller-manager force-detach volumes and evict pods.
+type outOfServiceRemediator struct {
+	r *MachineReconciler
+}
+
+func (or *outOfServiceRemediator) Remediate(ctx context.Context, machine *machinev1beta1.Machine) (v1alpha1.RemediationPhase, error) {
+	r := or.r
+
+	if machine.Status.NodeRef == nil {
+		return v1alpha1.PhaseFailed, nil
+	}
+
+	node := &v1.Node{}
+	if err := r.Get(ctx, clientKeyFor(machine.Status.NodeRef.Name), node); err != nil {
+		if errors.IsNotFound(err) {
+			r.setPhase(ctx, machine, v1alpha1.PhaseSucceeded)
+			return v1alpha1.PhaseSucceeded, nil
+		}
+		return v1alpha1.PhaseFailed, err
+	}
+
+	changed := false
+	if !node.Spec.Unschedulable {
+		node.Spec.Unschedulable = true
+		changed = true
+	}
+	if !hasTaint(node, outOfServiceTaint) {
+		node.Spec.Taints = append(node.Spec.Taints, *outOfServiceTaint)
+		changed = true
+	}
+	if changed {
+		if err := r.Update(ctx, node); err != nil {
+			return v1alpha1.PhaseFailed, err
+		}
+	}
+
+	r.setPhase(ctx, machine, v1alpha1.PhaseNodeIsolated)
+	r.recordEvent(ctx, machine, node, "OutOfServiceTainted", "applied out-of-service taint, kube-controller-manager will force-detach volumes")
+
+	delete(machine.Annotations, externalRemediationAnnotation)
+	if err := r.Update(ctx, machine); err != nil {
+		return v1alpha1.PhaseFailed, err
+	}
+
+	r.setPhase(ctx, machine, v1alpha1.PhaseSucceeded)
+	return v1alpha1.PhaseSucceeded, nil
+}
+
+func hasTaint(node *v1.Node, taint *v1.Taint) bool {
+	for _, t := range node.Spec.Taints {
+		if t.Key == taint.Key && t.Effect == taint.Effect {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	machinev1beta1 "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/medik8s/self-node-remediation/api/v1alpha1"
+)
+
+var _ = Describe("Machine Controller remediation strategies", func() {
+	var machine *machinev1beta1.Machine
+	var node *v1.Node
+
+	newUnhealthyMachine := func(name, strategy string) (*machinev1beta1.Machine, *v1.Node) {
+		n := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name + "-node"}}
+		Expect(k8sClient.Create(context.TODO(), n)).To(Succeed())
+
+		m := &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: machineNamespace,
+				Annotations: map[string]string{
+					externalRemediationAnnotation: "",
+				},
+			},
+		}
+		if strategy != "" {
+			m.Annotations[remediationStrategyAnnotation] = strategy
+		}
+		Expect(k8sClient.Create(context.TODO(), m)).To(Succeed())
+		m.Status.NodeRef = &v1.ObjectReference{Kind: "Node", Name: n.Name}
+		Expect(k8sClient.Status().Update(context.TODO(), m)).To(Succeed())
+
+		return m, n
+	}
+
+	Context("Reprovision strategy", func() {
+		It("deletes the Machine instead of restoring the Node", func() {
+			machine, node = newUnhealthyMachine("reprovision-machine", string(StrategyReprovision))
+
+			fresh := newFreshReconciler()
+			_, err := fresh.Reconcile(context.TODO(), reconcileRequestFor(machine))
+			Expect(err).ToNot(HaveOccurred())
+
+			Eventually(func() error {
+				return k8sClient.Get(context.TODO(), client.ObjectKey{Name: machine.Name, Namespace: machine.Namespace}, &machinev1beta1.Machine{})
+			}, 5*time.Second, 250*time.Millisecond).Should(HaveOccurred())
+
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: node.Name}, &v1.Node{})).To(Succeed())
+		})
+	})
+
+	Context("OutOfService strategy", func() {
+		It("applies the out-of-service taint without deleting the Node", func() {
+			machine, node = newUnhealthyMachine("oos-machine", string(StrategyOutOfService))
+
+			fresh := newFreshReconciler()
+			_, err := fresh.Reconcile(context.TODO(), reconcileRequestFor(machine))
+			Expect(err).ToNot(HaveOccurred())
+
+			Eventually(func() bool {
+				n := &v1.Node{}
+				Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: node.Name}, n)).To(Succeed())
+				return hasTaint(n, outOfServiceTaint)
+			}, 5*time.Second, 250*time.Millisecond).Should(BeTrue())
+
+			// The in-memory status is cleared as soon as the remediation
+			// reaches a terminal phase, so assert against the persisted
+			// SelfNodeRemediation CR instead.
+			Eventually(func() v1alpha1.RemediationPhase {
+				cr := &v1alpha1.SelfNodeRemediation{}
+				Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: machine.Name, Namespace: machine.Namespace}, cr)).To(Succeed())
+				return cr.Status.Phase
+			}, 5*time.Second, 250*time.Millisecond).Should(Equal(v1alpha1.PhaseSucceeded))
+		})
+	})
+
+	AfterEach(func() {
+		if machine != nil {
+			_ = k8sClient.Delete(context.TODO(), machine)
+		}
+		if node != nil {
+			_ = k8sClient.Delete(context.TODO(), node)
+		}
+		machine, node = nil, nil
+	})
+})
@@ -0,0 +1,17 @@
+package controllers
+
+import "time"
+
+// Watchdog abstracts the hardware (or software) watchdog device the agent
+// feeds while the node is healthy. Starving it causes the node to reboot
+// once its timeout elapses.
+type Watchdog interface {
+	// Start begins feeding the watchdog on a fixed interval.
+	Start() error
+	// Feed resumes feeding the watchdog after Starve.
+	Feed()
+	// Starve stops feeding the watchdog so it will trigger a reboot.
+	Starve()
+	// GetLastFoodTime returns the last time the watchdog was fed.
+	GetLastFoodTime() time.Time
+}
@@ -0,0 +1,103 @@
+package peerhealth
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	peerhealthv1 "github.com/medik8s/self-node-remediation/api/grpc/peerhealth/v1"
+)
+
+// defaultGRPCPort is the port agents serve the PeerHealth gRPC service on,
+// alongside their existing HTTP peer endpoint.
+const defaultGRPCPort = 30001
+
+// dialTimeout bounds how long a single peer dial may take before the caller
+// falls back to the legacy HTTP probe.
+const dialTimeout = 2 * time.Second
+
+// Client asks a single peer agent for a node's health, preferring gRPC and
+// falling back to the legacy HTTP endpoint when the peer's gRPC port can't
+// be reached (e.g. an older agent that hasn't rolled out the gRPC server
+// yet).
+type Client struct {
+	tlsConfig  *tls.Config
+	httpClient *http.Client
+}
+
+// NewClient builds a peer client. tlsConfig, when non-nil, is used for
+// mutual TLS on both the gRPC and the HTTP fallback transports, using the
+// same certificate material the agents already mount for their HTTP peer
+// endpoint.
+func NewClient(tlsConfig *tls.Config) *Client {
+	httpClient := &http.Client{Timeout: dialTimeout}
+	if tlsConfig != nil {
+		httpClient.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	}
+	return &Client{tlsConfig: tlsConfig, httpClient: httpClient}
+}
+
+// IsHealthy asks the peer at peerAddress (host, no port) whether nodeName
+// looks healthy from its point of view.
+func (c *Client) IsHealthy(ctx context.Context, peerAddress, nodeName string) (peerhealthv1.HealthResult, error) {
+	result, err := c.isHealthyGRPC(ctx, peerAddress, nodeName)
+	if err == nil {
+		return result, nil
+	}
+	return c.isHealthyHTTP(ctx, peerAddress, nodeName)
+}
+
+func (c *Client) isHealthyGRPC(ctx context.Context, peerAddress, nodeName string) (peerhealthv1.HealthResult, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	opts := []grpc.DialOption{grpc.WithBlock()}
+	if c.tlsConfig != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(c.tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.DialContext(dialCtx, fmt.Sprintf("%s:%d", peerAddress, defaultGRPCPort), opts...)
+	if err != nil {
+		return peerhealthv1.HealthResult_API_ERROR, err
+	}
+	defer conn.Close()
+
+	resp, err := peerhealthv1.NewPeerHealthClient(conn).IsHealthy(ctx, &peerhealthv1.HealthRequest{NodeName: nodeName})
+	if err != nil {
+		return peerhealthv1.HealthResult_API_ERROR, err
+	}
+	return resp.GetResult(), nil
+}
+
+// isHealthyHTTP is the pre-existing peer check, kept as a fallback for
+// peers whose gRPC port is unreachable (firewalled, not yet rolled out,
+// etc). It mirrors the historical GET /health/<nodeName> contract.
+func (c *Client) isHealthyHTTP(ctx context.Context, peerAddress, nodeName string) (peerhealthv1.HealthResult, error) {
+	url := fmt.Sprintf("https://%s/health/%s", peerAddress, nodeName)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return peerhealthv1.HealthResult_API_ERROR, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return peerhealthv1.HealthResult_API_ERROR, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return peerhealthv1.HealthResult_HEALTHY, nil
+	case http.StatusNotFound:
+		return peerhealthv1.HealthResult_UNHEALTHY, nil
+	default:
+		return peerhealthv1.HealthResult_API_ERROR, fmt.Errorf("unexpected status from peer %s: %d", peerAddress, resp.StatusCode)
+	}
+}
@@ -0,0 +1,104 @@
+package peerhealth
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	peerhealthv1 "github.com/medik8s/self-node-remediation/api/grpc/peerhealth/v1"
+)
+
+// HealthSource is consulted by the Server to answer IsHealthy/StreamHealth
+// requests; the agent's existing health-evaluation logic implements it.
+type HealthSource interface {
+	IsHealthy(nodeName string) peerhealthv1.HealthResult
+}
+
+// Server serves the PeerHealth gRPC service alongside the agent's existing
+// HTTP peer endpoint.
+type Server struct {
+	peerhealthv1.UnimplementedPeerHealthServer
+
+	source HealthSource
+	tls    *tls.Config
+
+	mu          sync.Mutex
+	subscribers map[peerhealthv1.PeerHealth_StreamHealthServer]string
+}
+
+// NewServer builds a Server backed by source. tlsConfig, when non-nil, is
+// used for mTLS using the same certificate material as the HTTP endpoint.
+func NewServer(source HealthSource, tlsConfig *tls.Config) *Server {
+	return &Server{
+		source:      source,
+		tls:         tlsConfig,
+		subscribers: map[peerhealthv1.PeerHealth_StreamHealthServer]string{},
+	}
+}
+
+// Serve starts the gRPC server on addr and blocks until it stops.
+func (s *Server) Serve(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	var opts []grpc.ServerOption
+	if s.tls != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.tls)))
+	}
+
+	srv := grpc.NewServer(opts...)
+	peerhealthv1.RegisterPeerHealthServer(srv, s)
+	return srv.Serve(lis)
+}
+
+// IsHealthy implements the single-shot RPC.
+func (s *Server) IsHealthy(_ context.Context, req *peerhealthv1.HealthRequest) (*peerhealthv1.HealthResponse, error) {
+	return &peerhealthv1.HealthResponse{Result: s.source.IsHealthy(req.GetNodeName())}, nil
+}
+
+// StreamHealth implements the bidirectional push RPC: for every node name
+// the peer sends, reply once immediately. Each stream is subscribed only to
+// the node name it sent most recently, and NotifyChanged pushes again only
+// to streams subscribed to the changed node.
+func (s *Server) StreamHealth(stream peerhealthv1.PeerHealth_StreamHealthServer) error {
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			s.mu.Lock()
+			delete(s.subscribers, stream)
+			s.mu.Unlock()
+			return err
+		}
+
+		s.mu.Lock()
+		s.subscribers[stream] = req.GetNodeName()
+		s.mu.Unlock()
+
+		if err := stream.Send(&peerhealthv1.HealthResponse{Result: s.source.IsHealthy(req.GetNodeName())}); err != nil {
+			return err
+		}
+	}
+}
+
+// NotifyChanged proactively pushes the current health of nodeName to every
+// subscriber watching it, e.g. right after this agent detects it lost
+// access to the kube-apiserver.
+func (s *Server) NotifyChanged(nodeName string) {
+	result := s.source.IsHealthy(nodeName)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for stream, watched := range s.subscribers {
+		if watched != nodeName {
+			continue
+		}
+		_ = stream.Send(&peerhealthv1.HealthResponse{Result: result})
+	}
+}